@@ -0,0 +1,142 @@
+package prquadtree
+
+import "math"
+
+// Mass may be implemented by a stored value to give it a mass other than
+// the default of 1 for the purposes of center-of-mass aggregation and
+// ApproxForce.
+type Mass interface {
+	Mass() float64
+}
+
+// Vec2 is a 2D vector, used for force accumulation in ApproxForce.
+type Vec2 struct {
+	X float64
+	Y float64
+}
+
+func massOf[V any](val V) float64 {
+	if m, ok := any(val).(Mass); ok {
+		return m.Mass()
+	}
+	return 1
+}
+
+// recomputeMass recalculates the leaf's aggregate mass and center of mass
+// from its current entries.
+func (leaf *Leaf[T, V]) recomputeMass() {
+	var total, cx, cy float64
+	for _, entry := range leaf.entries {
+		for _, v := range entry.elems {
+			m := massOf(v)
+			total += m
+			cx += float64(entry.point.X) * m
+			cy += float64(entry.point.Y) * m
+		}
+	}
+	if total > 0 {
+		cx /= total
+		cy /= total
+	}
+	leaf.totalMass = total
+	leaf.centerOfMass = Point[float64]{cx, cy}
+}
+
+// recomputeMass recalculates the node's aggregate mass and center of mass
+// from its 4 children's already-current aggregates.
+func (node *Node[T, V]) recomputeMass() {
+	var total, cx, cy float64
+	for _, slot := range node.nodes {
+		var m, x, y float64
+		switch next := slot.(type) {
+		case *Node[T, V]:
+			m, x, y = next.totalMass, next.centerOfMass.X, next.centerOfMass.Y
+		case *Leaf[T, V]:
+			m, x, y = next.totalMass, next.centerOfMass.X, next.centerOfMass.Y
+		default:
+			continue
+		}
+		if m == 0 {
+			continue
+		}
+		total += m
+		cx += x * m
+		cy += y * m
+	}
+	if total > 0 {
+		cx /= total
+		cy /= total
+	}
+	node.totalMass = total
+	node.centerOfMass = Point[float64]{cx, cy}
+}
+
+// CenterOfMass returns the aggregate center of mass of every value stored
+// in this subtree.
+func (node *Node[T, V]) CenterOfMass() Point[float64] {
+	return node.centerOfMass
+}
+
+// TotalMass returns the aggregate mass of every value stored in this
+// subtree, per Mass where implemented and 1 otherwise.
+func (node *Node[T, V]) TotalMass() float64 {
+	return node.totalMass
+}
+
+// ApproxForce computes a Barnes-Hut approximation of the net force (or any
+// other pairwise accumulation g computes) exerted on target by every value
+// stored in the tree. A cell whose side length s satisfies s/d < theta,
+// where d is the distance from target to that cell's center of mass, is
+// treated as a single pseudo-particle at its center of mass; otherwise
+// ApproxForce recurses into a *Node's children, or sums a bucketed *Leaf's
+// entries individually. Smaller theta means more exact (and more
+// expensive); theta == 0 degenerates to an exact O(n) sum even when a leaf
+// buckets several distinct points together.
+func (node *Node[T, V]) ApproxForce(target Point[float64], theta float64, g func(src, dst Point[float64], m float64) Vec2) Vec2 {
+	var total Vec2
+	for i, slot := range node.nodes {
+		switch next := slot.(type) {
+		case *Leaf[T, V]:
+			if next.totalMass == 0 {
+				continue
+			}
+			dx := next.centerOfMass.X - target.X
+			dy := next.centerOfMass.Y - target.Y
+			d := math.Sqrt(dx*dx + dy*dy)
+			childBounds := quadrantBounds(node.bounds, i)
+			side := 2 * math.Max(float64(childBounds.HalfDim.X), float64(childBounds.HalfDim.Y))
+			if d > 0 && side/d < theta {
+				f := g(next.centerOfMass, target, next.totalMass)
+				total.X += f.X
+				total.Y += f.Y
+				continue
+			}
+			for _, entry := range next.entries {
+				src := Point[float64]{X: float64(entry.point.X), Y: float64(entry.point.Y)}
+				for _, v := range entry.elems {
+					f := g(src, target, massOf(v))
+					total.X += f.X
+					total.Y += f.Y
+				}
+			}
+		case *Node[T, V]:
+			if next.totalMass == 0 {
+				continue
+			}
+			dx := next.centerOfMass.X - target.X
+			dy := next.centerOfMass.Y - target.Y
+			d := math.Sqrt(dx*dx + dy*dy)
+			side := 2 * math.Max(float64(next.bounds.HalfDim.X), float64(next.bounds.HalfDim.Y))
+			if d > 0 && side/d < theta {
+				f := g(next.centerOfMass, target, next.totalMass)
+				total.X += f.X
+				total.Y += f.Y
+			} else {
+				f := next.ApproxForce(target, theta, g)
+				total.X += f.X
+				total.Y += f.Y
+			}
+		}
+	}
+	return total
+}
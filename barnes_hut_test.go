@@ -0,0 +1,104 @@
+package prquadtree
+
+import (
+	"math"
+	"testing"
+)
+
+// body is a point mass used to exercise the Barnes-Hut augmentation.
+type body struct {
+	mass float64
+}
+
+func (b body) Mass() float64 { return b.mass }
+
+const gravitationalConstant = 1.0
+
+func gravity(src, dst Point[float64], m float64) Vec2 {
+	dx := src.X - dst.X
+	dy := src.Y - dst.Y
+	distSq := dx*dx + dy*dy
+	if distSq == 0 {
+		return Vec2{}
+	}
+	dist := math.Sqrt(distSq)
+	f := gravitationalConstant * m / distSq
+	return Vec2{X: f * dx / dist, Y: f * dy / dist}
+}
+
+// bruteForce computes the same force accumulation directly, for comparison.
+func bruteForce(points []Point[float64], masses []float64, target Point[float64]) Vec2 {
+	var total Vec2
+	for i, p := range points {
+		f := gravity(p, target, masses[i])
+		total.X += f.X
+		total.Y += f.Y
+	}
+	return total
+}
+
+func TestApproxForce(t *testing.T) {
+	tree := NewTree[float64, body](-100, 100, -100, 100, 0)
+
+	points := []Point[float64]{
+		{10, 10},
+		{-20, 15},
+		{5, -30},
+		{-40, -5},
+	}
+	masses := []float64{5, 3, 7, 2}
+
+	for i, p := range points {
+		if err := tree.Insert(p, body{mass: masses[i]}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got, want := tree.TotalMass(), 5.0+3.0+7.0+2.0; got != want {
+		t.Errorf("TotalMass() = %v, want %v", got, want)
+	}
+
+	target := Point[float64]{50, 50}
+
+	// theta == 0 forces exact traversal: should match brute force exactly.
+	exact := tree.ApproxForce(target, 0, gravity)
+	want := bruteForce(points, masses, target)
+	if math.Abs(exact.X-want.X) > 1e-9 || math.Abs(exact.Y-want.Y) > 1e-9 {
+		t.Errorf("ApproxForce(theta=0) = %v, want %v", exact, want)
+	}
+
+	// A generous theta should still approximate the brute-force result
+	// within a reasonable tolerance for a target far from the bodies.
+	approx := tree.ApproxForce(target, 1.0, gravity)
+	const tolerance = 0.1
+	if math.Abs(approx.X-want.X) > tolerance || math.Abs(approx.Y-want.Y) > tolerance {
+		t.Errorf("ApproxForce(theta=1.0) = %v, want approximately %v", approx, want)
+	}
+}
+
+// A bucketed leaf holds several distinct points collapsed under one
+// center-of-mass pseudo-particle; theta == 0 must still sum them
+// individually rather than treating the whole leaf as one exact body.
+func TestApproxForceBucketedLeafExact(t *testing.T) {
+	tree := NewTreeWithBucket[float64, body](-100, 100, -100, 100, 0, 4, 0)
+
+	points := []Point[float64]{
+		{10, 10},
+		{10.5, 10.5},
+		{11, 9.5},
+	}
+	masses := []float64{5, 3, 7}
+
+	for i, p := range points {
+		if err := tree.Insert(p, body{mass: masses[i]}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	target := Point[float64]{50, 50}
+	exact := tree.ApproxForce(target, 0, gravity)
+	want := bruteForce(points, masses, target)
+	if math.Abs(exact.X-want.X) > 1e-9 || math.Abs(exact.Y-want.Y) > 1e-9 {
+		t.Errorf("ApproxForce(theta=0) over a bucketed leaf = %v, want %v", exact, want)
+	}
+}
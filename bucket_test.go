@@ -0,0 +1,100 @@
+package prquadtree
+
+import "testing"
+
+// With a bucket of 3, a leaf should accumulate up to 3 distinct points
+// before a 4th, still-distinct point in the same quadrant forces a split.
+func TestBucketCapacity(t *testing.T) {
+	tree := NewTreeWithBucket[int, int](0, 8, 0, 8, 0, 3, 0)
+
+	// All of these fall in quadrant 0 (west, not south) of the root.
+	points := []Point[int]{{1, 5}, {2, 6}, {3, 7}}
+	for i, p := range points {
+		if err := tree.Insert(p, i); err != nil {
+			t.Fatal(err)
+		}
+		leaf, ok := tree.nodes[0].(*Leaf[int, int])
+		if !ok {
+			t.Fatalf("after %d inserts, expected a leaf at quadrant 0, got %T", i+1, tree.nodes[0])
+		}
+		if len(leaf.entries) != i+1 {
+			t.Errorf("after %d inserts, expected %d entries, got %d", i+1, i+1, len(leaf.entries))
+		}
+	}
+
+	// A 4th distinct point in the same quadrant overflows the bucket.
+	if err := tree.Insert(Point[int]{1, 6}, 99); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := tree.nodes[0].(*Node[int, int]); !ok {
+		t.Fatalf("expected bucket overflow to split quadrant 0 into a node, got %T", tree.nodes[0])
+	}
+
+	for i, p := range append(points, Point[int]{1, 6}) {
+		want := i
+		if i == len(points) {
+			want = 99
+		}
+		validateFind(t, tree, p, want)
+	}
+}
+
+// maxDepth should stop subdivision even when the bucket is full, bucketing
+// further distinct points into the leaf instead.
+func TestMaxDepth(t *testing.T) {
+	tree := NewTreeWithBucket[int, int](0, 8, 0, 8, 0, 1, 1)
+
+	validateInsert(t, tree, Point[int]{1, 5}, 1)
+	validateInsert(t, tree, Point[int]{2, 6}, 2)
+	validateInsert(t, tree, Point[int]{3, 7}, 3)
+
+	leaf, ok := tree.nodes[0].(*Leaf[int, int])
+	if !ok {
+		t.Fatalf("expected maxDepth to keep quadrant 0 a leaf, got %T", tree.nodes[0])
+	}
+	if len(leaf.entries) != 3 {
+		t.Errorf("expected 3 bucketed entries, got %d", len(leaf.entries))
+	}
+
+	validateFind(t, tree, Point[int]{1, 5}, 1)
+	validateFind(t, tree, Point[int]{2, 6}, 2)
+	validateFind(t, tree, Point[int]{3, 7}, 3)
+}
+
+// Deleting a point's last value from a leaf that still holds other, distinct
+// points must splice the point's leafEntry out entirely, not just empty its
+// elems. Otherwise the point lingers as a zombie entry: findable as an empty
+// result, reported present by hasPoint, and able to steal a reinsert's seq.
+func TestBucketDeleteRemovesEntry(t *testing.T) {
+	tree := NewTreeWithBucket[int, int](0, 8, 0, 8, 0, 3, 0)
+
+	validateInsert(t, tree, Point[int]{1, 5}, 1)
+	validateInsert(t, tree, Point[int]{2, 6}, 2)
+
+	if !tree.Delete(Point[int]{1, 5}, 1) {
+		t.Fatal("Delete failed for a valid point/value")
+	}
+
+	leaf, ok := tree.nodes[0].(*Leaf[int, int])
+	if !ok {
+		t.Fatalf("expected quadrant 0 to remain a leaf, got %T", tree.nodes[0])
+	}
+	if len(leaf.entries) != 1 {
+		t.Errorf("expected the deleted point's entry to be spliced out, got %d entries", len(leaf.entries))
+	}
+
+	validateFind(t, tree, Point[int]{1, 5})
+	validateFind(t, tree, Point[int]{2, 6}, 2)
+
+	if tree.hasPoint(Point[int]{1, 5}) {
+		t.Error("hasPoint reports a deleted point as still present")
+	}
+
+	staleSeq := leaf.entries[0].seq
+	validateInsert(t, tree, Point[int]{1, 5}, 3)
+	for _, entry := range leaf.entries {
+		if entry.point == (Point[int]{1, 5}) && entry.seq <= staleSeq {
+			t.Errorf("reinsert reused a stale seq %d instead of minting a fresh one", entry.seq)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package prquadtree
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// A Result is one match from NearestK: the point found, every value stored
+// there, and its squared distance from the query target.
+type Result[T Number, V any] struct {
+	Point  Point[T]
+	Values []V
+	DistSq T
+
+	// seq is the point's insertion order, used only to break ties at equal
+	// DistSq so NearestK's output doesn't depend on traversal order.
+	seq int
+}
+
+// nearestHeap is a max-heap of at most k results, ordered by DistSq (ties
+// broken by seq, larger is worse) so that the current worst candidate is
+// always at the root and can be evicted in O(log k) once a closer, or
+// earlier-inserted equally close, one is found.
+type nearestHeap[T Number, V any] []Result[T, V]
+
+func (h nearestHeap[T, V]) Len() int { return len(h) }
+func (h nearestHeap[T, V]) Less(i, j int) bool {
+	if h[i].DistSq != h[j].DistSq {
+		return h[i].DistSq > h[j].DistSq
+	}
+	return h[i].seq > h[j].seq
+}
+func (h nearestHeap[T, V]) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *nearestHeap[T, V]) Push(x interface{}) { *h = append(*h, x.(Result[T, V])) }
+func (h *nearestHeap[T, V]) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+func (h *nearestHeap[T, V]) offer(k int, r Result[T, V]) {
+	if h.Len() < k {
+		heap.Push(h, r)
+		return
+	}
+	top := (*h)[0]
+	if r.DistSq < top.DistSq || (r.DistSq == top.DistSq && r.seq < top.seq) {
+		heap.Pop(h)
+		heap.Push(h, r)
+	}
+}
+
+func pointDistSq[T Number](a, b Point[T]) T {
+	dx := a.X - b.X
+	dy := a.Y - b.Y
+	return dx*dx + dy*dy
+}
+
+// rectDistSq returns the squared distance from p to the nearest point of b,
+// which is 0 when p is inside b.
+func rectDistSq[T Number](b BoundingBox[T], p Point[T]) T {
+	dx := clampedDist(p.X, b.Center.X-b.HalfDim.X, b.Center.X+b.HalfDim.X)
+	dy := clampedDist(p.Y, b.Center.Y-b.HalfDim.Y, b.Center.Y+b.HalfDim.Y)
+	return dx*dx + dy*dy
+}
+
+func clampedDist[T Number](v, lo, hi T) T {
+	switch {
+	case v < lo:
+		return lo - v
+	case v > hi:
+		return v - hi
+	default:
+		return 0
+	}
+}
+
+// a candidate is either an unexplored child node (child set) or a leaf
+// entry ready to be scored (elems set), paired with its distance (exact
+// for a leaf entry, a lower bound for a child node).
+type candidate[T Number, V any] struct {
+	distSq T
+	point  Point[T]
+	elems  []V
+	seq    int
+	child  *Node[T, V]
+}
+
+// NearestK returns the up-to-k points closest to target, nearest first.
+// target need not be within the tree's bounds. Ties at equal distance are
+// broken by insertion order: whichever point was inserted first wins a
+// contested k-th slot.
+func (node *Node[T, V]) NearestK(target Point[T], k int) []Result[T, V] {
+	if k <= 0 {
+		return nil
+	}
+
+	h := &nearestHeap[T, V]{}
+	node.nearestK(target, k, h)
+
+	results := make([]Result[T, V], h.Len())
+	for i := len(results) - 1; i >= 0; i-- {
+		results[i] = heap.Pop(h).(Result[T, V])
+	}
+	return results
+}
+
+func (node *Node[T, V]) nearestK(target Point[T], k int, h *nearestHeap[T, V]) {
+	candidates := make([]candidate[T, V], 0, 4)
+	for _, slot := range node.nodes {
+		switch next := slot.(type) {
+		case *Node[T, V]:
+			candidates = append(candidates, candidate[T, V]{distSq: rectDistSq(next.bounds, target), child: next})
+		case *Leaf[T, V]:
+			for _, entry := range next.entries {
+				candidates = append(candidates, candidate[T, V]{
+					distSq: pointDistSq(entry.point, target),
+					point:  entry.point,
+					elems:  entry.elems,
+					seq:    entry.seq,
+				})
+			}
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].distSq < candidates[j].distSq })
+
+	for _, c := range candidates {
+		if h.Len() == k && c.distSq > (*h)[0].DistSq {
+			// Everything past here is at least this far away too.
+			break
+		}
+		if c.child != nil {
+			c.child.nearestK(target, k, h)
+			continue
+		}
+		h.offer(k, Result[T, V]{Point: c.point, Values: c.elems, DistSq: c.distSq, seq: c.seq})
+	}
+}
+
+// Nearest returns the single point closest to target, along with every
+// value stored there. The bool result is false if the tree is empty.
+func (node *Node[T, V]) Nearest(target Point[T]) (Point[T], []V, bool) {
+	results := node.NearestK(target, 1)
+	if len(results) == 0 {
+		var zero Point[T]
+		return zero, nil, false
+	}
+	return results[0].Point, results[0].Values, true
+}
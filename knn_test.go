@@ -0,0 +1,157 @@
+package prquadtree
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// linearNearestK is a brute-force reference implementation used to check
+// NearestK against.
+func linearNearestK(points []Point[int], vals []int, target Point[int], k int) []Result[int, int] {
+	type scored struct {
+		idx    int
+		distSq int
+	}
+	scores := make([]scored, len(points))
+	for i, p := range points {
+		scores[i] = scored{idx: i, distSq: pointDistSq(p, target)}
+	}
+	sort.SliceStable(scores, func(i, j int) bool { return scores[i].distSq < scores[j].distSq })
+
+	if k > len(scores) {
+		k = len(scores)
+	}
+	results := make([]Result[int, int], k)
+	for i := 0; i < k; i++ {
+		results[i] = Result[int, int]{
+			Point:  points[scores[i].idx],
+			Values: []int{vals[scores[i].idx]},
+			DistSq: scores[i].distSq,
+		}
+	}
+	return results
+}
+
+func TestNearestK(t *testing.T) {
+	tree := NewTree[int, int](-50, 50, -50, 50, 0)
+
+	rand.Seed(1)
+	var points []Point[int]
+	var vals []int
+	for i := 0; i < 200; i++ {
+		p := Point[int]{rand.Intn(100) - 50, rand.Intn(100) - 50}
+		if tree.Find(p) != nil {
+			continue // keep points distinct so the linear reference is unambiguous
+		}
+		validateInsert(t, tree, p, i)
+		points = append(points, p)
+		vals = append(vals, i)
+	}
+
+	targets := []Point[int]{{0, 0}, {49, 49}, {-50, 50}, {200, 200}, {-200, -200}}
+	for _, target := range targets {
+		for _, k := range []int{1, 5, 10} {
+			got := tree.NearestK(target, k)
+			want := linearNearestK(points, vals, target, k)
+			if len(got) != len(want) {
+				t.Fatalf("target %v k=%d: got %d results, want %d", target, k, len(got), len(want))
+			}
+			for i := range got {
+				if got[i].DistSq != want[i].DistSq || got[i].Point != want[i].Point {
+					t.Errorf("target %v k=%d index %d: got %+v, want %+v",
+						target, k, i, got[i], want[i])
+				}
+			}
+		}
+	}
+}
+
+func TestNearest(t *testing.T) {
+	tree := NewTree[int, int](-10, 10, -10, 10, 0)
+
+	if _, _, ok := tree.Nearest(Point[int]{0, 0}); ok {
+		t.Error("Expected Nearest to fail on an empty tree")
+	}
+
+	validateInsert(t, tree, Point[int]{3, 4}, 1)
+	validateInsert(t, tree, Point[int]{-3, -4}, 2)
+
+	point, vals, ok := tree.Nearest(Point[int]{2, 2})
+	if !ok {
+		t.Fatal("Expected Nearest to succeed")
+	}
+	if point != (Point[int]{3, 4}) {
+		t.Errorf("Expected nearest point {3 4}, got %v", point)
+	}
+	if len(vals) != 1 || vals[0] != 1 {
+		t.Errorf("Expected values [1], got %v", vals)
+	}
+}
+
+// A bucketed leaf can hold several distinct points. Deleting every value at
+// one of them must not leave a zombie entry that NearestK/Nearest surface
+// with an empty Values slice.
+func TestNearestKAfterDelete(t *testing.T) {
+	tree := NewTreeWithBucket[int, int](-10, 10, -10, 10, 0, 2, 0)
+
+	validateInsert(t, tree, Point[int]{0, 0}, 1)
+	validateInsert(t, tree, Point[int]{1, 0}, 2)
+
+	if !tree.Delete(Point[int]{0, 0}, 1) {
+		t.Fatal("Delete failed for a valid point/value")
+	}
+
+	for _, r := range tree.NearestK(Point[int]{0, 0}, 2) {
+		if len(r.Values) == 0 {
+			t.Errorf("NearestK surfaced a deleted point with no values: %+v", r)
+		}
+	}
+
+	point, vals, ok := tree.Nearest(Point[int]{0, 0})
+	if !ok {
+		t.Fatal("Expected Nearest to succeed")
+	}
+	if point != (Point[int]{1, 0}) || len(vals) != 1 || vals[0] != 2 {
+		t.Errorf("Expected the surviving point {1 0}/[2], got %v/%v", point, vals)
+	}
+}
+
+func buildRandomTree(n int) (*Node[int, int], []Point[int]) {
+	tree := NewTree[int, int](-1000, 1000, -1000, 1000, 0)
+	points := make([]Point[int], 0, n)
+	rand.Seed(2)
+	for len(points) < n {
+		p := Point[int]{rand.Intn(2000) - 1000, rand.Intn(2000) - 1000}
+		if tree.Find(p) != nil {
+			continue
+		}
+		tree.Insert(p, len(points))
+		points = append(points, p)
+	}
+	return tree, points
+}
+
+func BenchmarkNearestKTree(b *testing.B) {
+	tree, _ := buildRandomTree(1000)
+	target := Point[int]{0, 0}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.NearestK(target, 10)
+	}
+}
+
+func BenchmarkNearestKLinear(b *testing.B) {
+	_, points := buildRandomTree(1000)
+	vals := make([]int, len(points))
+	for i := range vals {
+		vals[i] = i
+	}
+	target := Point[int]{0, 0}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		linearNearestK(points, vals, target, 10)
+	}
+}
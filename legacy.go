@@ -0,0 +1,17 @@
+package prquadtree
+
+// This file is a thin backward-compatibility shim over the generic API for
+// callers that only ever used integer coordinates and interface{} values,
+// the shape this package had before it grew type parameters.
+
+// IntPoint is the pre-generics Point: integer coordinates.
+type IntPoint = Point[int]
+
+// IntNode is the pre-generics Node: integer coordinates, interface{} values.
+type IntNode = Node[int, interface{}]
+
+// NewIntTree builds an integer-coordinate tree with a minimum cell size of
+// 1, matching the old behavior where subdivision bottomed out at a 1x1 cell.
+func NewIntTree(xmin, xmax, ymin, ymax int) *IntNode {
+	return NewTree[int, interface{}](xmin, xmax, ymin, ymax, 1)
+}
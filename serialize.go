@@ -0,0 +1,538 @@
+package prquadtree
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// Type registry for decoding values stored as interface{} (as in the
+// legacy IntNode API): JSON and gob have no way to know which concrete
+// type to allocate for an interface{} field, so a caller that stores
+// anything beyond JSON's own primitive types must register that type's
+// name first, mirroring how encoding/gob requires gob.Register.
+var typeRegistry = map[string]reflect.Type{}
+var typeRegistryByType = map[reflect.Type]string{}
+
+// RegisterType makes name resolvable when decoding a value stored in a
+// Node[T, V] where V is interface{} (or another interface type). zero
+// should be a value of the concrete type being registered; its own value
+// is ignored. Safe to call from an init function.
+func RegisterType(name string, zero interface{}) {
+	t := reflect.TypeOf(zero)
+	typeRegistry[name] = t
+	typeRegistryByType[t] = name
+}
+
+// taggedValue is how a value is represented on the wire when V is an
+// interface type: a type name (if registered) alongside the JSON encoding
+// of the concrete value, so UnmarshalJSON/ReadFrom can reconstruct it.
+type taggedValue struct {
+	Type  string          `json:"type,omitempty"`
+	Value json.RawMessage `json:"value"`
+}
+
+func isInterfaceType[V any]() bool {
+	var v V
+	t := reflect.TypeOf(&v).Elem()
+	return t.Kind() == reflect.Interface
+}
+
+func encodeElem[V any](val V) (json.RawMessage, error) {
+	if !isInterfaceType[V]() {
+		return json.Marshal(val)
+	}
+
+	concrete := reflect.ValueOf(val)
+	var name string
+	if concrete.IsValid() {
+		name = typeRegistryByType[concrete.Type()]
+	}
+	raw, err := json.Marshal(val)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(taggedValue{Type: name, Value: raw})
+}
+
+func decodeElem[V any](raw json.RawMessage) (V, error) {
+	var zero V
+	if !isInterfaceType[V]() {
+		var v V
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return zero, err
+		}
+		return v, nil
+	}
+
+	var tagged taggedValue
+	if err := json.Unmarshal(raw, &tagged); err != nil {
+		// Fall back to whatever encoding/json can infer (bool, float64,
+		// string, []interface{}, map[string]interface{}).
+		var v interface{}
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return zero, err
+		}
+		result, ok := v.(V)
+		if !ok {
+			return zero, fmt.Errorf("prquadtree: cannot decode untagged value as %T", zero)
+		}
+		return result, nil
+	}
+	if tagged.Type == "" {
+		// Unregistered concrete type: decode the wrapped value itself, not
+		// the taggedValue envelope, and let encoding/json infer its type
+		// (bool, float64, string, []interface{}, map[string]interface{}).
+		var v interface{}
+		if err := json.Unmarshal(tagged.Value, &v); err != nil {
+			return zero, err
+		}
+		result, ok := v.(V)
+		if !ok {
+			return zero, fmt.Errorf("prquadtree: cannot decode untagged value as %T", zero)
+		}
+		return result, nil
+	}
+
+	t, ok := typeRegistry[tagged.Type]
+	if !ok {
+		return zero, fmt.Errorf("prquadtree: type %q was never registered with RegisterType", tagged.Type)
+	}
+	ptr := reflect.New(t)
+	if err := json.Unmarshal(tagged.Value, ptr.Interface()); err != nil {
+		return zero, err
+	}
+	result, ok := ptr.Elem().Interface().(V)
+	if !ok {
+		return zero, fmt.Errorf("prquadtree: registered type %q is not assignable to %T", tagged.Type, zero)
+	}
+	return result, nil
+}
+
+// entryJSON is the wire representation of a leafEntry.
+type entryJSON[T Number, V any] struct {
+	Point Point[T]          `json:"point"`
+	Elems []json.RawMessage `json:"elems"`
+	Seq   int               `json:"seq"`
+}
+
+type leafJSON[T Number, V any] struct {
+	Entries []entryJSON[T, V] `json:"entries"`
+}
+
+// MarshalJSON encodes leaf's entries, each a point and the values stored at
+// it.
+func (leaf *Leaf[T, V]) MarshalJSON() ([]byte, error) {
+	aux := leafJSON[T, V]{Entries: make([]entryJSON[T, V], len(leaf.entries))}
+	for i, entry := range leaf.entries {
+		elems := make([]json.RawMessage, len(entry.elems))
+		for j, v := range entry.elems {
+			raw, err := encodeElem(v)
+			if err != nil {
+				return nil, err
+			}
+			elems[j] = raw
+		}
+		aux.Entries[i] = entryJSON[T, V]{Point: entry.point, Elems: elems, Seq: entry.seq}
+	}
+	return json.Marshal(aux)
+}
+
+// UnmarshalJSON decodes a leaf previously written by MarshalJSON.
+func (leaf *Leaf[T, V]) UnmarshalJSON(data []byte) error {
+	var aux leafJSON[T, V]
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	entries := make([]leafEntry[T, V], len(aux.Entries))
+	for i, e := range aux.Entries {
+		elems := make([]V, len(e.Elems))
+		for j, raw := range e.Elems {
+			v, err := decodeElem[V](raw)
+			if err != nil {
+				return err
+			}
+			elems[j] = v
+		}
+		entries[i] = leafEntry[T, V]{point: e.Point, elems: elems, seq: e.Seq}
+	}
+	leaf.entries = entries
+	leaf.recomputeMass()
+	return nil
+}
+
+// quadrantJSON holds exactly one of Leaf or Node, mirroring the dynamic
+// type stored in Node.nodes; a nil slot marshals to JSON null since
+// quadrantJSON itself is referenced through a pointer.
+type quadrantJSON[T Number, V any] struct {
+	Leaf *Leaf[T, V] `json:"leaf,omitempty"`
+	Node *Node[T, V] `json:"node,omitempty"`
+}
+
+type nodeJSON[T Number, V any] struct {
+	Boundary    BoundingBox[T]         `json:"boundary"`
+	MinCellSize T                      `json:"minCellSize"`
+	Bucket      int                    `json:"bucket"`
+	MaxDepth    int                    `json:"maxDepth"`
+	Depth       int                    `json:"depth"`
+	Quadrants   [4]*quadrantJSON[T, V] `json:"quadrants"`
+}
+
+// MarshalJSON encodes node and every node or leaf beneath it, preserving
+// quadrant order (0..3, per the package comment) with nil children encoded
+// as JSON null.
+func (node *Node[T, V]) MarshalJSON() ([]byte, error) {
+	aux := nodeJSON[T, V]{
+		Boundary:    node.bounds,
+		MinCellSize: node.minCellSize,
+		Bucket:      node.bucket,
+		MaxDepth:    node.maxDepth,
+		Depth:       node.depth,
+	}
+	for i, slot := range node.nodes {
+		switch next := slot.(type) {
+		case *Node[T, V]:
+			aux.Quadrants[i] = &quadrantJSON[T, V]{Node: next}
+		case *Leaf[T, V]:
+			aux.Quadrants[i] = &quadrantJSON[T, V]{Leaf: next}
+		}
+	}
+	return json.Marshal(aux)
+}
+
+// UnmarshalJSON decodes a tree previously written by MarshalJSON.
+func (node *Node[T, V]) UnmarshalJSON(data []byte) error {
+	var aux nodeJSON[T, V]
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	node.bounds = aux.Boundary
+	node.minCellSize = aux.MinCellSize
+	node.bucket = aux.Bucket
+	node.maxDepth = aux.MaxDepth
+	node.depth = aux.Depth
+	node.nodes = [4]interface{}{}
+	for i, q := range aux.Quadrants {
+		switch {
+		case q == nil:
+			node.nodes[i] = nil
+		case q.Leaf != nil:
+			node.nodes[i] = q.Leaf
+		case q.Node != nil:
+			node.nodes[i] = q.Node
+		}
+	}
+	node.recomputeMass()
+	node.seq = new(int)
+	*node.seq = maxSeqInSubtree(node) + 1
+	return nil
+}
+
+// maxSeqInSubtree returns the largest leafEntry.seq anywhere under node, or
+// -1 if it holds no entries. UnmarshalJSON/ReadFrom use it so a tree's
+// insertion-order counter carries on from where it left off, instead of
+// restarting at 0 and colliding with seq values already on disk.
+func maxSeqInSubtree[T Number, V any](node *Node[T, V]) int {
+	max := -1
+	for _, slot := range node.nodes {
+		var m int
+		switch next := slot.(type) {
+		case *Node[T, V]:
+			m = maxSeqInSubtree(next)
+		case *Leaf[T, V]:
+			for _, entry := range next.entries {
+				if entry.seq > m {
+					m = entry.seq
+				}
+			}
+		default:
+			continue
+		}
+		if m > max {
+			max = m
+		}
+	}
+	return max
+}
+
+// Binary format: a small header (magic, version, bounds, minCellSize,
+// bucket, maxDepth) followed by a preorder traversal of the tree with a
+// one-byte tag per slot. Child bounds and depth are never written: they're
+// fully determined by the parent's bounds/depth and the slot's quadrant
+// index, via quadrantBounds.
+const (
+	binaryMagic   uint32 = 0x50525154 // "PRQT"
+	binaryVersion uint8  = 1
+
+	tagNil byte = iota
+	tagLeaf
+	tagNode
+)
+
+// WriteTo writes node and its entire subtree in the package's compact
+// binary format.
+func (node *Node[T, V]) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+
+	if err := binary.Write(&buf, binary.BigEndian, binaryMagic); err != nil {
+		return 0, err
+	}
+	if err := binary.Write(&buf, binary.BigEndian, binaryVersion); err != nil {
+		return 0, err
+	}
+	if err := writeBounds(&buf, node.bounds); err != nil {
+		return 0, err
+	}
+	if err := binary.Write(&buf, binary.BigEndian, float64(node.minCellSize)); err != nil {
+		return 0, err
+	}
+	if err := binary.Write(&buf, binary.BigEndian, int32(node.bucket)); err != nil {
+		return 0, err
+	}
+	if err := binary.Write(&buf, binary.BigEndian, int32(node.maxDepth)); err != nil {
+		return 0, err
+	}
+	if err := node.writeSubtree(&buf); err != nil {
+		return 0, err
+	}
+
+	n, err := w.Write(buf.Bytes())
+	return int64(n), err
+}
+
+func (node *Node[T, V]) writeSubtree(buf *bytes.Buffer) error {
+	for _, slot := range node.nodes {
+		switch next := slot.(type) {
+		case nil:
+			buf.WriteByte(tagNil)
+		case *Leaf[T, V]:
+			buf.WriteByte(tagLeaf)
+			if err := writeLeaf(buf, next); err != nil {
+				return err
+			}
+		case *Node[T, V]:
+			buf.WriteByte(tagNode)
+			if err := next.writeSubtree(buf); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("prquadtree: unexpected node type %T", next)
+		}
+	}
+	return nil
+}
+
+func writeBounds[T Number](buf *bytes.Buffer, b BoundingBox[T]) error {
+	for _, v := range []T{b.Center.X, b.Center.Y, b.HalfDim.X, b.HalfDim.Y} {
+		if err := binary.Write(buf, binary.BigEndian, float64(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readBounds[T Number](r io.Reader) (BoundingBox[T], error) {
+	var vals [4]float64
+	for i := range vals {
+		if err := binary.Read(r, binary.BigEndian, &vals[i]); err != nil {
+			return BoundingBox[T]{}, err
+		}
+	}
+	return BoundingBox[T]{
+		Center:  Point[T]{T(vals[0]), T(vals[1])},
+		HalfDim: Point[T]{T(vals[2]), T(vals[3])},
+	}, nil
+}
+
+func writeLeaf[T Number, V any](buf *bytes.Buffer, leaf *Leaf[T, V]) error {
+	if err := binary.Write(buf, binary.BigEndian, int32(len(leaf.entries))); err != nil {
+		return err
+	}
+	for _, entry := range leaf.entries {
+		if err := writePoint(buf, entry.point); err != nil {
+			return err
+		}
+		if err := binary.Write(buf, binary.BigEndian, int32(entry.seq)); err != nil {
+			return err
+		}
+		if err := binary.Write(buf, binary.BigEndian, int32(len(entry.elems))); err != nil {
+			return err
+		}
+		for _, v := range entry.elems {
+			raw, err := encodeElem(v)
+			if err != nil {
+				return err
+			}
+			if err := binary.Write(buf, binary.BigEndian, int32(len(raw))); err != nil {
+				return err
+			}
+			buf.Write(raw)
+		}
+	}
+	return nil
+}
+
+func writePoint[T Number](buf *bytes.Buffer, p Point[T]) error {
+	if err := binary.Write(buf, binary.BigEndian, float64(p.X)); err != nil {
+		return err
+	}
+	return binary.Write(buf, binary.BigEndian, float64(p.Y))
+}
+
+func readPoint[T Number](r io.Reader) (Point[T], error) {
+	var x, y float64
+	if err := binary.Read(r, binary.BigEndian, &x); err != nil {
+		return Point[T]{}, err
+	}
+	if err := binary.Read(r, binary.BigEndian, &y); err != nil {
+		return Point[T]{}, err
+	}
+	return Point[T]{T(x), T(y)}, nil
+}
+
+// ReadFrom reads a tree previously written by (*Node[T, V]).WriteTo.
+func ReadFrom[T Number, V any](r io.Reader) (*Node[T, V], int64, error) {
+	counter := &countingReader{r: r}
+
+	var magic uint32
+	if err := binary.Read(counter, binary.BigEndian, &magic); err != nil {
+		return nil, counter.n, err
+	}
+	if magic != binaryMagic {
+		return nil, counter.n, fmt.Errorf("prquadtree: bad magic %#x", magic)
+	}
+
+	var version uint8
+	if err := binary.Read(counter, binary.BigEndian, &version); err != nil {
+		return nil, counter.n, err
+	}
+	if version != binaryVersion {
+		return nil, counter.n, fmt.Errorf("prquadtree: unsupported version %d", version)
+	}
+
+	bounds, err := readBounds[T](counter)
+	if err != nil {
+		return nil, counter.n, err
+	}
+
+	var minCellSize float64
+	if err := binary.Read(counter, binary.BigEndian, &minCellSize); err != nil {
+		return nil, counter.n, err
+	}
+	var bucket, maxDepth int32
+	if err := binary.Read(counter, binary.BigEndian, &bucket); err != nil {
+		return nil, counter.n, err
+	}
+	if err := binary.Read(counter, binary.BigEndian, &maxDepth); err != nil {
+		return nil, counter.n, err
+	}
+
+	root := &Node[T, V]{
+		bounds:      bounds,
+		minCellSize: T(minCellSize),
+		bucket:      int(bucket),
+		maxDepth:    int(maxDepth),
+	}
+	if err := root.readSubtree(counter); err != nil {
+		return nil, counter.n, err
+	}
+	root.recomputeMass()
+	root.seq = new(int)
+	*root.seq = maxSeqInSubtree(root) + 1
+	return root, counter.n, nil
+}
+
+func (node *Node[T, V]) readSubtree(r io.Reader) error {
+	for i := range node.nodes {
+		var tag byte
+		if err := binary.Read(r, binary.BigEndian, &tag); err != nil {
+			return err
+		}
+		switch tag {
+		case tagNil:
+			node.nodes[i] = nil
+		case tagLeaf:
+			leaf, err := readLeaf[T, V](r)
+			if err != nil {
+				return err
+			}
+			node.nodes[i] = leaf
+		case tagNode:
+			child := &Node[T, V]{
+				bounds:      quadrantBounds(node.bounds, i),
+				minCellSize: node.minCellSize,
+				bucket:      node.bucket,
+				maxDepth:    node.maxDepth,
+				depth:       node.depth + 1,
+			}
+			if err := child.readSubtree(r); err != nil {
+				return err
+			}
+			child.recomputeMass()
+			node.nodes[i] = child
+		default:
+			return fmt.Errorf("prquadtree: unknown slot tag %d", tag)
+		}
+	}
+	return nil
+}
+
+func readLeaf[T Number, V any](r io.Reader) (*Leaf[T, V], error) {
+	var entryCount int32
+	if err := binary.Read(r, binary.BigEndian, &entryCount); err != nil {
+		return nil, err
+	}
+
+	leaf := &Leaf[T, V]{entries: make([]leafEntry[T, V], entryCount)}
+	for i := int32(0); i < entryCount; i++ {
+		point, err := readPoint[T](r)
+		if err != nil {
+			return nil, err
+		}
+		var seq int32
+		if err := binary.Read(r, binary.BigEndian, &seq); err != nil {
+			return nil, err
+		}
+		var elemCount int32
+		if err := binary.Read(r, binary.BigEndian, &elemCount); err != nil {
+			return nil, err
+		}
+		elems := make([]V, elemCount)
+		for j := int32(0); j < elemCount; j++ {
+			var rawLen int32
+			if err := binary.Read(r, binary.BigEndian, &rawLen); err != nil {
+				return nil, err
+			}
+			raw := make([]byte, rawLen)
+			if _, err := io.ReadFull(r, raw); err != nil {
+				return nil, err
+			}
+			v, err := decodeElem[V](raw)
+			if err != nil {
+				return nil, err
+			}
+			elems[j] = v
+		}
+		leaf.entries[i] = leafEntry[T, V]{point: point, elems: elems, seq: int(seq)}
+	}
+	leaf.recomputeMass()
+	return leaf, nil
+}
+
+// countingReader wraps an io.Reader to track bytes read, for ReadFrom's
+// byte-count return value.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
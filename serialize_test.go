@@ -0,0 +1,128 @@
+package prquadtree
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func build21x21Tree(t *testing.T) *Node[int, int] {
+	tree := NewTree[int, int](-10, 10, -10, 10, 0)
+	for y := -10; y <= 10; y++ {
+		for x := -10; x <= 10; x++ {
+			expected := (y+10)*21 + (x + 10)
+			if err := tree.Insert(Point[int]{x, y}, expected); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return tree
+}
+
+func assertSameFindRange(t *testing.T, original, restored *Node[int, int], label string) {
+	query := NewBoundingBox(-10, 10, -10, 10)
+	want := original.FindRange(query, nil)
+	got := restored.FindRange(query, nil)
+
+	sortInts := func(vals []int) {
+		for i := 1; i < len(vals); i++ {
+			for j := i; j > 0 && vals[j-1] > vals[j]; j-- {
+				vals[j-1], vals[j] = vals[j], vals[j-1]
+			}
+		}
+	}
+	sortInts(want)
+	sortInts(got)
+
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("%s: FindRange mismatch after round-trip: got %d values, want %d", label, len(got), len(want))
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	tree := build21x21Tree(t)
+
+	data, err := json.Marshal(tree)
+	if err != nil {
+		t.Fatalf("MarshalJSON failed: %v", err)
+	}
+
+	restored := &Node[int, int]{}
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatalf("UnmarshalJSON failed: %v", err)
+	}
+
+	assertSameFindRange(t, tree, restored, "JSON")
+}
+
+// taggedPayload is a concrete struct registered via RegisterType, used
+// alongside a bare primitive to exercise both branches of decodeElem for a
+// Node[int, interface{}] tree.
+type taggedPayload struct {
+	Name string
+}
+
+func TestInterfaceElemRoundTrip(t *testing.T) {
+	RegisterType("taggedPayload", taggedPayload{})
+
+	tree := NewTree[int, interface{}](-10, 10, -10, 10, 0)
+	if err := tree.Insert(Point[int]{1, 1}, taggedPayload{Name: "registered"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tree.Insert(Point[int]{2, 2}, "bare string"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tree.Insert(Point[int]{3, 3}, 3.5); err != nil {
+		t.Fatal(err)
+	}
+
+	jsonData, err := json.Marshal(tree)
+	if err != nil {
+		t.Fatalf("MarshalJSON failed: %v", err)
+	}
+	jsonRestored := &Node[int, interface{}]{}
+	if err := json.Unmarshal(jsonData, jsonRestored); err != nil {
+		t.Fatalf("UnmarshalJSON failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := tree.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	binRestored, _, err := ReadFrom[int, interface{}](&buf)
+	if err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+
+	for label, restored := range map[string]*Node[int, interface{}]{"JSON": jsonRestored, "binary": binRestored} {
+		if got := restored.Find(Point[int]{1, 1}); len(got) != 1 || !reflect.DeepEqual(got[0], taggedPayload{Name: "registered"}) {
+			t.Errorf("%s: registered type round-trip, got %v", label, got)
+		}
+		if got := restored.Find(Point[int]{2, 2}); len(got) != 1 || got[0] != "bare string" {
+			t.Errorf("%s: bare string round-trip, got %v", label, got)
+		}
+		if got := restored.Find(Point[int]{3, 3}); len(got) != 1 || got[0] != 3.5 {
+			t.Errorf("%s: bare float round-trip, got %v", label, got)
+		}
+	}
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	tree := build21x21Tree(t)
+
+	var buf bytes.Buffer
+	if _, err := tree.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+
+	restored, n, err := ReadFrom[int, int](&buf)
+	if err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+	if n == 0 {
+		t.Error("ReadFrom reported reading 0 bytes")
+	}
+
+	assertSameFindRange(t, tree, restored, "binary")
+}
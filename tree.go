@@ -1,10 +1,17 @@
-package prquadtree
-
-import (
-	"errors"
-	"fmt"
-)
-
+// Package prquadtree implements a point-region quadtree: a spatial index
+// that stores points (each carrying zero or more values) and answers
+// bounding-box and exact-point lookups in roughly O(log n) time.
+//
+// Each leaf can be configured to hold more than one distinct point (a
+// "bucket"), per NewTreeWithBucket. A bucket of 1 gives the classic
+// behavior of splitting on the very next distinct point; a larger bucket
+// trades a bit of linear scanning within a leaf for a shallower tree with
+// fewer, cheaper-to-allocate internal nodes, which tends to win when points
+// cluster tightly. MaxDepth bounds that tradeoff from the other direction:
+// it caps subdivision regardless of bucket overflow, which combined with
+// minCellSize keeps a flood of coincident or near-coincident points from
+// recursing forever.
+//
 // A node has 4 quadrants in clockwise order:
 //  (-1, 1) ==================== (1, 1)
 //          |   0    |    1    |
@@ -13,244 +20,503 @@ import (
 //          |        |         |
 //          |   3    |    2    |
 // (-1, -1) ==================== (1, -1)
-type Node struct {
-	// For convenience
-	nw Point
-	se Point
+package prquadtree
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Number is the set of coordinate types a Point, BoundingBox or Tree may be
+// parameterized over: any integer or floating point type.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// A Point is a single coordinate pair.
+type Point[T Number] struct {
+	X T
+	Y T
+}
+
+// A BoundingBox is an axis-aligned rectangle described by its center and its
+// half-dimension (the distance from the center to each edge along X and Y).
+// This is the convention used throughout the package instead of the
+// corner-pair (nw/se) convention, since it makes exact quadrant subdivision
+// straightforward: each child's half-dimension is simply the parent's halved.
+type BoundingBox[T Number] struct {
+	Center  Point[T]
+	HalfDim Point[T]
+}
+
+// NewBoundingBox builds a BoundingBox from a min/max corner pair, the more
+// familiar way to describe a region. For an integer T an odd-sized corner
+// pair (an even number of coordinates, e.g. xmin=-7, xmax=-6) has no exact
+// center+half-dimension equivalent, since that representation always spans
+// an odd count of coordinates along each axis; in that case the box rounds
+// up rather than down, so it may extend a unit past xmax/ymax but never
+// fails to cover the requested range.
+func NewBoundingBox[T Number](xmin, xmax, ymin, ymax T) BoundingBox[T] {
+	halfDim := Point[T]{ceilHalf(xmax - xmin), ceilHalf(ymax - ymin)}
+	return BoundingBox[T]{
+		Center:  Point[T]{xmin + halfDim.X, ymin + halfDim.Y},
+		HalfDim: halfDim,
+	}
+}
+
+// Contains reports whether p falls within b, inclusive of the boundary.
+func (b BoundingBox[T]) Contains(p Point[T]) bool {
+	return p.X >= b.Center.X-b.HalfDim.X && p.X <= b.Center.X+b.HalfDim.X &&
+		p.Y >= b.Center.Y-b.HalfDim.Y && p.Y <= b.Center.Y+b.HalfDim.Y
+}
+
+// Intersects reports whether b and other overlap, including edge contact.
+func (b BoundingBox[T]) Intersects(other BoundingBox[T]) bool {
+	return absDiff(b.Center.X, other.Center.X) <= b.HalfDim.X+other.HalfDim.X &&
+		absDiff(b.Center.Y, other.Center.Y) <= b.HalfDim.Y+other.HalfDim.Y
+}
+
+func absDiff[T Number](a, b T) T {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+// A Node is both the root of a tree and every internal node within it. It
+// can hold up to 4 children, each of which is nil, a *Node[T, V], or a
+// *Leaf[T, V].
+type Node[T Number, V any] struct {
+	bounds BoundingBox[T]
+
+	// minCellSize stops subdivision once a child's half-dimension would
+	// drop to or below it, so that points which are arbitrarily close
+	// together (or, in the integer case, adjacent) don't force infinite
+	// recursion. Once reached, colliding points are bucketed together in
+	// the same Leaf instead of forcing another split.
+	minCellSize T
+
+	// bucket is how many distinct points a Leaf may hold before a new,
+	// distinct point forces it to split into a Node (subject to
+	// minCellSize/maxDepth still allowing a split at all).
+	bucket int
+
+	// maxDepth, if positive, caps how many levels deep the tree may
+	// subdivide regardless of bucket overflow. 0 means unlimited.
+	maxDepth int
 
-	// Can contain nil, Node* or Leaf*
+	// depth is this node's distance from the root, used to enforce
+	// maxDepth.
+	depth int
+
+	// seq is a counter shared by every node in the tree, used to stamp each
+	// newly-inserted distinct point with its insertion order. NearestK uses
+	// it to break ties at equal distance. It's lazily allocated so a Node
+	// decoded by UnmarshalJSON/ReadFrom (which never runs Insert) doesn't
+	// need one until it does.
+	seq *int
+
+	// Can contain nil, *Node[T, V] or *Leaf[T, V]
 	nodes [4]interface{}
+
+	// totalMass and centerOfMass aggregate every value in the subtree
+	// rooted here that implements Mass (values that don't count as mass
+	// 1), for use by ApproxForce. They're recomputed after every Insert
+	// and Delete that touches this subtree.
+	totalMass    float64
+	centerOfMass Point[float64]
 }
 
-// Leafs can store multiple values
-type Leaf struct {
-	point Point
-	elems []interface{}
+// A leafEntry pairs a distinct point with the values stored at it. seq
+// records the insertion order of this distinct point relative to every
+// other point in the tree, for NearestK's tie-breaking.
+type leafEntry[T Number, V any] struct {
+	point Point[T]
+	elems []V
+	seq   int
 }
 
-type Point struct {
-	x int
-	y int
+// A Leaf stores one or more distinct points that all fall within the same
+// minimum-size cell, each with its own values. In the common case there is
+// exactly one entry; more than one only occurs once subdivision has bottomed
+// out at minCellSize.
+type Leaf[T Number, V any] struct {
+	entries []leafEntry[T, V]
+
+	// totalMass and centerOfMass mirror the fields on Node; see there.
+	totalMass    float64
+	centerOfMass Point[float64]
 }
 
-// Given the nw and se boundaries of a rectangle and a point, determine which
-// quadrant that point resides in if the rectangle were to be split in half vertically
-// and horizontally.  Returns the quadrant as well as the updated boundaries
-// for the chosen quad.
-func chooseQuadrant(nw Point, se Point, point Point) (Point, Point, int) {
-	center := Point{(nw.x + se.x) / 2, (nw.y + se.y) / 2}
-
-	westernHemisphere := true
-	southernHemisphere := true
-
-	// rounding truncation means we favor western for positive coords
-	// and east for negative coords so handle the case where width or
-	// height is only 2 units specially
-	if se.x-nw.x == 1 {
-		westernHemisphere = point.x == nw.x
-	} else {
-		westernHemisphere = point.x <= center.x
-		if westernHemisphere {
-			se.x = center.x
-		} else {
-			nw.x = center.x
+func (leaf *Leaf[T, V]) hasPoint(point Point[T]) bool {
+	for i := range leaf.entries {
+		if leaf.entries[i].point == point {
+			return true
 		}
 	}
+	return false
+}
 
-	if nw.y-se.y == 1 {
-		southernHemisphere = point.y == se.y
-	} else {
-		southernHemisphere = point.y <= center.y
-		if southernHemisphere {
-			nw.y = center.y
-		} else {
-			se.y = center.y
+func (leaf *Leaf[T, V]) insert(point Point[T], val V, seq int) {
+	for i := range leaf.entries {
+		if leaf.entries[i].point == point {
+			leaf.entries[i].elems = append(leaf.entries[i].elems, val)
+			leaf.recomputeMass()
+			return
 		}
 	}
+	leaf.entries = append(leaf.entries, leafEntry[T, V]{point: point, elems: []V{val}, seq: seq})
+	leaf.recomputeMass()
+}
 
-	quadrant := 0
-	if westernHemisphere {
-		// quadrant 0 or 3
-		if southernHemisphere {
-			quadrant = 3
-		} else {
-			quadrant = 0
+func (leaf *Leaf[T, V]) find(point Point[T]) []V {
+	for i := range leaf.entries {
+		if leaf.entries[i].point == point {
+			return leaf.entries[i].elems
 		}
-	} else {
-		// quadrant 1 or 2
-		if southernHemisphere {
-			quadrant = 2
-		} else {
-			quadrant = 1
+	}
+	return nil
+}
+
+// delete removes one value equal to val at point, reporting whether
+// anything was removed. Equality is checked with reflect.DeepEqual since V
+// is unconstrained and may not support ==.
+func (leaf *Leaf[T, V]) delete(point Point[T], val V) bool {
+	for i := range leaf.entries {
+		entry := &leaf.entries[i]
+		if entry.point != point {
+			continue
 		}
+		for j, curr := range entry.elems {
+			if reflect.DeepEqual(curr, val) {
+				length := len(entry.elems)
+				entry.elems[j] = entry.elems[length-1]
+				entry.elems = entry.elems[:length-1]
+				if len(entry.elems) == 0 {
+					leaf.entries[i] = leaf.entries[len(leaf.entries)-1]
+					leaf.entries = leaf.entries[:len(leaf.entries)-1]
+				}
+				leaf.recomputeMass()
+				return true
+			}
+		}
+		return false
 	}
+	return false
+}
 
-	return nw, se, quadrant
+// empty reports whether the leaf holds no entries. Entries with no values
+// left are spliced out by delete as soon as they go empty, so unlike a
+// point-by-point length check this only needs to look at len(entries).
+func (leaf *Leaf[T, V]) empty() bool {
+	return len(leaf.entries) == 0
 }
 
-func (leaf *Leaf) insert(point Point, val interface{}) {
-	if point != leaf.point {
-		panic(fmt.Sprintf(
-			"Tried to insert at leaf %s for val destined for %s", leaf.point, point))
+// chooseQuadrant determines which of bounds' 4 quadrants point falls in,
+// returning that quadrant's own bounds alongside its index. Because bounds
+// is center+half-dimension, the child's half-dimension is always an exact
+// halving of the parent's, with no corner-rounding involved.
+func chooseQuadrant[T Number](bounds BoundingBox[T], point Point[T]) (BoundingBox[T], int) {
+	west := point.X <= bounds.Center.X
+	south := point.Y <= bounds.Center.Y
+
+	var quadrant int
+	switch {
+	case west && !south:
+		quadrant = 0
+	case !west && !south:
+		quadrant = 1
+	case !west && south:
+		quadrant = 2
+	default:
+		quadrant = 3
 	}
 
-	leaf.elems = append(leaf.elems, val)
+	return quadrantBounds(bounds, quadrant), quadrant
+}
+
+// nextHalfDim returns the half-dimension a child spanning half of a parent
+// with the given half-dimension should use. It rounds up (not down) so
+// that, for an odd integer half-dimension, the two children's bounds
+// overlap by a unit at the split line rather than leaving a unit-wide gap
+// neither child claims to cover: Contains/Intersects must never be too
+// small for what a subtree actually holds, only too generous.
+func nextHalfDim[T Number](h Point[T]) Point[T] {
+	return Point[T]{ceilHalf(h.X), ceilHalf(h.Y)}
 }
 
-func (node *Node) Insert(point Point, val interface{}) error {
-	if !node.inBounds(point) {
-		return errors.New("Attempt to insert point out of bounds")
+func ceilHalf[T Number](v T) T {
+	half := v / 2
+	if half*2 != v {
+		half++
 	}
+	return half
+}
 
-	nw, se, quadrant := chooseQuadrant(node.nw, node.se, point)
-	if node.nodes[quadrant] == nil {
-		var leaf *Leaf = &Leaf{point, nil}
-		leaf.insert(point, val)
-		node.nodes[quadrant] = leaf
-	} else {
-		switch next := node.nodes[quadrant].(type) {
-		case *Node:
-			return next.Insert(point, val)
-		case *Leaf:
-			if next.point == point {
-				next.insert(point, val)
-			} else {
-				// Replace leaf with node and call recursively
-				var newNode = new(Node)
-				newNode.nw = nw
-				newNode.se = se
-				for _, oldVal := range next.elems {
-					if err := newNode.Insert(next.point, oldVal); err != nil {
-						panic(err)
-					}
-				}
-				if err := newNode.Insert(point, val); err != nil {
-					panic(err)
-				}
-				node.nodes[quadrant] = newNode
-			}
-		default:
-			panic("Unexpected node type")
-		}
+// quadrantBounds returns the bounds of bounds' given quadrant (0..3, per
+// the package comment). Unlike chooseQuadrant it needs no point: a
+// quadrant's bounds are fully determined by its parent's and its own
+// index, which lets callers like the binary codec reconstruct a subtree's
+// bounds without having to store them.
+func quadrantBounds[T Number](bounds BoundingBox[T], quadrant int) BoundingBox[T] {
+	half := nextHalfDim(bounds.HalfDim)
+	switch quadrant {
+	case 0:
+		return BoundingBox[T]{Center: Point[T]{bounds.Center.X - half.X, bounds.Center.Y + half.Y}, HalfDim: half}
+	case 1:
+		return BoundingBox[T]{Center: Point[T]{bounds.Center.X + half.X, bounds.Center.Y + half.Y}, HalfDim: half}
+	case 2:
+		return BoundingBox[T]{Center: Point[T]{bounds.Center.X + half.X, bounds.Center.Y - half.Y}, HalfDim: half}
+	default:
+		return BoundingBox[T]{Center: Point[T]{bounds.Center.X - half.X, bounds.Center.Y - half.Y}, HalfDim: half}
 	}
+}
 
-	return nil
+// canSubdivide reports whether a slot with the given bounds may still be
+// split into a new Node, per the tree's configured minCellSize and
+// maxDepth. It also stops once another split would no longer shrink the
+// half-dimension at all, which for integer types happens once it reaches
+// 1: ceilHalf(1) is still 1, so splitting further would just recreate the
+// same bounds forever instead of converging.
+func (node *Node[T, V]) canSubdivide(bounds BoundingBox[T]) bool {
+	if node.maxDepth > 0 && node.depth+1 >= node.maxDepth {
+		return false
+	}
+	if bounds.HalfDim.X <= node.minCellSize || bounds.HalfDim.Y <= node.minCellSize {
+		return false
+	}
+	next := nextHalfDim(bounds.HalfDim)
+	return next.X < bounds.HalfDim.X && next.Y < bounds.HalfDim.Y
 }
 
-func inBounds(nw, se, point Point) bool {
-	return point.x >= nw.x &&
-		point.x <= se.x &&
-		point.y >= se.y &&
-		point.y <= nw.y
+// Insert adds val at point, returning an error if point falls outside the
+// tree's bounds.
+func (node *Node[T, V]) Insert(point Point[T], val V) error {
+	if !node.bounds.Contains(point) {
+		return errors.New("prquadtree: attempt to insert point out of bounds")
+	}
+	if node.seq == nil {
+		node.seq = new(int)
+	}
+	seq := *node.seq
+	if !node.hasPoint(point) {
+		*node.seq++
+	}
+	node.insert(point, val, seq)
+	return nil
 }
 
-func (node *Node) inBounds(point Point) bool {
-	return inBounds(node.nw, node.se, point)
+// hasPoint reports whether point already has an entry somewhere in the
+// tree, so Insert knows whether to mint a fresh sequence number for it.
+func (node *Node[T, V]) hasPoint(point Point[T]) bool {
+	_, quadrant := chooseQuadrant(node.bounds, point)
+	switch next := node.nodes[quadrant].(type) {
+	case *Node[T, V]:
+		return next.hasPoint(point)
+	case *Leaf[T, V]:
+		return next.hasPoint(point)
+	}
+	return false
 }
 
-func rectanglesCollide(nw1, se1, nw2, se2 Point) bool {
-	// Check rect2 contains rect1
-	xContains := nw2.x <= nw1.x && se2.x >= se1.x
-	yContains := nw2.y >= nw1.y && se2.y <= se1.y
-	xOverlap := (nw2.x >= nw1.x && nw2.x <= se1.x) ||
-		(se2.x >= nw1.x && se2.x <= se1.x)
-	yOverlap := (nw2.y >= se1.y && nw2.y <= nw1.y) ||
-		(se2.y >= se1.y && se2.y <= nw1.y)
+func (node *Node[T, V]) insert(point Point[T], val V, seq int) {
+	defer node.recomputeMass()
 
-	return (xOverlap || xContains) && (yOverlap || yContains)
-}
+	childBounds, quadrant := chooseQuadrant(node.bounds, point)
 
-func (node *Node) FindRange(nw, se Point, elems []interface{}) []interface{} {
-	for _, curr := range node.nodes {
-		if curr != nil {
-			switch next := curr.(type) {
-			case *Leaf:
-				if inBounds(nw, se, next.point) {
-					elems = append(elems, next.elems...)
-				}
-			case *Node:
-				if rectanglesCollide(nw, se, next.nw, next.se) {
-					elems = next.FindRange(nw, se, elems)
-				}
-			default:
-				panic("Unexpected node type")
+	switch next := node.nodes[quadrant].(type) {
+	case nil:
+		leaf := &Leaf[T, V]{}
+		leaf.insert(point, val, seq)
+		node.nodes[quadrant] = leaf
+	case *Leaf[T, V]:
+		if next.hasPoint(point) || len(next.entries) < node.bucket || !node.canSubdivide(childBounds) {
+			next.insert(point, val, seq)
+			return
+		}
+		// The leaf is full and distinct from point: replace it with a new
+		// node and reinsert its entries, each keeping its original seq.
+		newNode := &Node[T, V]{
+			bounds:      childBounds,
+			minCellSize: node.minCellSize,
+			bucket:      node.bucket,
+			maxDepth:    node.maxDepth,
+			depth:       node.depth + 1,
+			seq:         node.seq,
+		}
+		for _, entry := range next.entries {
+			for _, v := range entry.elems {
+				newNode.insert(entry.point, v, entry.seq)
 			}
 		}
+		newNode.insert(point, val, seq)
+		node.nodes[quadrant] = newNode
+	case *Node[T, V]:
+		next.insert(point, val, seq)
+	default:
+		panic("prquadtree: unexpected node type")
 	}
-	return elems
 }
 
-func (node *Node) Find(point Point) []interface{} {
-	if !node.inBounds(point) {
-		return nil
-	}
-
-	_, _, quadrant := chooseQuadrant(node.nw, node.se, point)
-	if node.nodes[quadrant] == nil {
+// Find returns every value stored at point, or nil if none are.
+func (node *Node[T, V]) Find(point Point[T]) []V {
+	if !node.bounds.Contains(point) {
 		return nil
 	}
+	return node.find(point)
+}
 
+// find is Find's recursive helper. Unlike Find it does not re-check bounds
+// containment at each level: a child's bounds can shrink asymmetrically
+// under integer halving, so re-checking Contains on the way down can reject
+// a point that chooseQuadrant still routes correctly.
+func (node *Node[T, V]) find(point Point[T]) []V {
+	_, quadrant := chooseQuadrant(node.bounds, point)
 	switch next := node.nodes[quadrant].(type) {
-	case *Node:
-		return next.Find(point)
-	case *Leaf:
-		if next.point.x == point.x && next.point.y == point.y {
-			return next.elems
-		}
-	default:
-		panic("Unexpected node type")
+	case *Node[T, V]:
+		return next.find(point)
+	case *Leaf[T, V]:
+		return next.find(point)
 	}
 	return nil
 }
 
-func (leaf *Leaf) Delete(point Point, val interface{}) bool {
-	if point.x != leaf.point.x || point.y != leaf.point.y {
-		return false
+// FindRange appends every value stored at a point within query to elems and
+// returns the result.
+func (node *Node[T, V]) FindRange(query BoundingBox[T], elems []V) []V {
+	for _, curr := range node.nodes {
+		switch next := curr.(type) {
+		case *Leaf[T, V]:
+			for _, entry := range next.entries {
+				if query.Contains(entry.point) {
+					elems = append(elems, entry.elems...)
+				}
+			}
+		case *Node[T, V]:
+			if query.Intersects(next.bounds) {
+				elems = next.FindRange(query, elems)
+			}
+		}
 	}
+	return elems
+}
 
-	for i, curr := range leaf.elems {
-		if curr == val {
-			length := len(leaf.elems)
-			leaf.elems[i] = leaf.elems[length-1]
-			leaf.elems = leaf.elems[:length-1]
-			return true
+// compactState describes what a deletion left behind in a node, so that the
+// caller one level up can keep the tree compact: an empty slot should be
+// nil'd out, and a node with exactly one remaining child that is itself a
+// leaf should be collapsed into that leaf, chaining back up the ancestors
+// for as long as each one also ends up with a single leaf child.
+type compactState int
+
+const (
+	compactUnchanged compactState = iota
+	compactEmpty
+	compactCollapsed
+)
+
+// compact inspects node's current children (after a deletion already
+// touched one of them) and reports how the caller should reflect that in
+// its own slot for node.
+func (node *Node[T, V]) compact() (compactState, *Leaf[T, V]) {
+	var count int
+	var onlyLeaf *Leaf[T, V]
+	for _, slot := range node.nodes {
+		if slot == nil {
+			continue
+		}
+		count++
+		if leaf, ok := slot.(*Leaf[T, V]); ok {
+			onlyLeaf = leaf
+		} else {
+			onlyLeaf = nil
 		}
 	}
-	return false
-}
 
-// This is a lame start to proper deletion.  This will not cleanup empty
-// leaf nodes in the tree.
-func (node *Node) Delete(point Point, val interface{}) bool {
-	if !node.inBounds(point) {
-		return false
+	switch {
+	case count == 0:
+		return compactEmpty, nil
+	case count == 1 && onlyLeaf != nil:
+		return compactCollapsed, onlyLeaf
+	default:
+		return compactUnchanged, nil
 	}
+}
 
-	_, _, quadrant := chooseQuadrant(node.nw, node.se, point)
-	if node.nodes[quadrant] == nil {
+// Delete removes one occurrence of val stored at point, reporting whether
+// anything was removed. It also prunes any leaf left empty by the removal
+// and collapses any resulting chain of single-leaf nodes back into one leaf
+// on the nearest ancestor with other children.
+func (node *Node[T, V]) Delete(point Point[T], val V) bool {
+	if !node.bounds.Contains(point) {
 		return false
 	}
+	ok, _, _ := node.delete(point, val)
+	return ok
+}
+
+func (node *Node[T, V]) delete(point Point[T], val V) (bool, compactState, *Leaf[T, V]) {
+	_, quadrant := chooseQuadrant(node.bounds, point)
+
 	switch next := node.nodes[quadrant].(type) {
-	case *Node:
-		return next.Delete(point, val)
-	case *Leaf:
-		return next.Delete(point, val)
+	case *Leaf[T, V]:
+		if !next.delete(point, val) {
+			return false, compactUnchanged, nil
+		}
+		if next.empty() {
+			node.nodes[quadrant] = nil
+		}
+	case *Node[T, V]:
+		ok, state, leaf := next.delete(point, val)
+		if !ok {
+			return false, compactUnchanged, nil
+		}
+		switch state {
+		case compactEmpty:
+			node.nodes[quadrant] = nil
+		case compactCollapsed:
+			node.nodes[quadrant] = leaf
+		}
 	default:
-		panic("Unexpected node type")
+		return false, compactUnchanged, nil
 	}
-	return false
+
+	node.recomputeMass()
+	state, leaf := node.compact()
+	return true, state, leaf
+}
+
+// NewTree is a helper to initialize a valid tree over the given bounds.
+// minCellSize configures how small a cell may get before subdivision stops
+// and colliding points are bucketed together in a single Leaf instead; pass
+// the zero value to subdivide without limit. Each leaf holds a single
+// distinct point before splitting; use NewTreeWithBucket for a larger
+// bucket.
+func NewTree[T Number, V any](xmin, xmax, ymin, ymax, minCellSize T) *Node[T, V] {
+	return NewTreeWithBucket[T, V](xmin, xmax, ymin, ymax, minCellSize, 1, 0)
 }
 
-// A Tree is just a Node, this is a helper to initialize a valid tree
-func NewTree(xmin, xmax, ymin, ymax int) *Node {
+// NewTreeWithBucket is like NewTree, but lets each leaf hold up to bucket
+// distinct points before a new, distinct point forces it to split, and caps
+// subdivision at maxDepth levels regardless of bucket overflow (0 means
+// unlimited). See the package comment for the tradeoff a larger bucket or a
+// smaller maxDepth makes.
+func NewTreeWithBucket[T Number, V any](xmin, xmax, ymin, ymax, minCellSize T, bucket, maxDepth int) *Node[T, V] {
 	if xmax <= xmin || ymax <= ymin {
-		panic(
-			fmt.Sprintf("Cannot create tree with boundaries: (x) %d-%d; (y)%d-%d",
-				xmin, xmax, ymin, ymax))
+		panic(fmt.Sprintf(
+			"prquadtree: cannot create tree with boundaries: (x) %v-%v; (y) %v-%v",
+			xmin, xmax, ymin, ymax))
+	}
+	if bucket < 1 {
+		panic("prquadtree: bucket must be at least 1")
 	}
 
-	newNode := new(Node)
-	newNode.nw = Point{xmin, ymax}
-	newNode.se = Point{xmax, ymin}
-	return newNode
+	return &Node[T, V]{
+		bounds:      NewBoundingBox(xmin, xmax, ymin, ymax),
+		minCellSize: minCellSize,
+		bucket:      bucket,
+		maxDepth:    maxDepth,
+	}
 }
@@ -8,7 +8,7 @@ import (
 	"time"
 )
 
-func validateFind(t *testing.T, node *Node, point Point, expected ...int) {
+func validateFind(t *testing.T, node *Node[int, int], point Point[int], expected ...int) {
 	vals := node.Find(point)
 	if len(vals) != len(expected) {
 		t.Errorf("Expected length of %d, but actually %d", len(expected), len(vals))
@@ -21,17 +21,15 @@ func validateFind(t *testing.T, node *Node, point Point, expected ...int) {
 	}
 }
 
-func validateFindRange(t *testing.T, node *Node, nw, se Point, expected ...int) {
+func validateFindRange(t *testing.T, node *Node[int, int], xmin, xmax, ymin, ymax int, expected ...int) {
 	var sorted []int
-	elems := node.FindRange(nw, se, nil)
+	elems := node.FindRange(NewBoundingBox(xmin, xmax, ymin, ymax), nil)
 
 	if len(elems) != len(expected) {
 		t.Errorf("Expected length of %d, but actually %d", len(expected), len(elems))
 	}
 
-	for _, curr := range elems {
-		sorted = append(sorted, curr.(int))
-	}
+	sorted = append(sorted, elems...)
 	sort.Ints(sorted)
 
 	for i, val := range expected {
@@ -41,7 +39,7 @@ func validateFindRange(t *testing.T, node *Node, nw, se Point, expected ...int)
 	}
 }
 
-func validateInsert(t *testing.T, node *Node, point Point, vals ...int) {
+func validateInsert(t *testing.T, node *Node[int, int], point Point[int], vals ...int) {
 	for _, val := range vals {
 		if err := node.Insert(point, val); err != nil {
 			t.Fatal(err)
@@ -49,100 +47,84 @@ func validateInsert(t *testing.T, node *Node, point Point, vals ...int) {
 	}
 }
 
-func modifyPoint(point Point, xdelta int, ydelta int) Point {
-	return Point{point.x + xdelta, point.y + ydelta}
-}
-
-func testRectangleCollision(
-	t *testing.T, nw1, se1, nw2, se2 Point, expectResult bool, msg string) {
-	if rectanglesCollide(nw1, se1, nw2, se2) != expectResult {
-		t.Errorf("Unexpected result %v for '%s' checking collision %vx%v %vx%v",
-			!expectResult, msg, nw1, se1, nw2, se2)
+func testBoundingBoxIntersects(
+	t *testing.T, a, b BoundingBox[int], expectResult bool, msg string) {
+	if a.Intersects(b) != expectResult {
+		t.Errorf("Unexpected result %v for '%s' checking intersection %+v / %+v",
+			!expectResult, msg, a, b)
 	}
 }
 
-func TestRectanglesCollide(t *testing.T) {
-	nw := Point{-2, 2}
-	se := Point{2, -2}
-
-	testRectangleCollision(t, nw, se, nw, se, true, "Same rectangle")
-	testRectangleCollision(t, nw, se, Point{-1, 1}, Point{1, -1}, true, "Inside contains")
-	testRectangleCollision(t, nw, se, Point{-3, 3}, Point{3, -3}, true, "Outside contains")
-	testRectangleCollision(t, nw, se, Point{0, 10}, Point{10, -10}, true, "Left edge")
-	testRectangleCollision(t, nw, se, Point{-10, 10}, Point{0, -10}, true, "Right edge")
-	testRectangleCollision(t, nw, se, Point{-10, 0}, Point{10, -10}, true, "Top edge")
-	testRectangleCollision(t, nw, se, Point{-10, 10}, Point{10, 0}, true, "Bottom edge")
-
-	testRectangleCollision(t, nw, se, Point{0, 0}, Point{10, -10}, true, "NW corner")
-	testRectangleCollision(t, nw, se, Point{-10, 0}, Point{0, -10}, true, "NE corner")
-	testRectangleCollision(t, nw, se, Point{-10, 10}, Point{0, 0}, true, "SE corner")
-	testRectangleCollision(t, nw, se, Point{0, 10}, Point{10, 0}, true, "SW corner")
-
-	testRectangleCollision(t, nw, se, Point{-10, 10}, nw, true, "NW point")
-	testRectangleCollision(t, nw, se, Point{-10, 10}, Point{-3, 2}, false, "NW point -1 west")
-	testRectangleCollision(t, nw, se, se, Point{10, -10}, true, "SE point")
-	testRectangleCollision(t, nw, se, Point{3, -2}, Point{10, -10}, false, "SE point +1 east")
-
-	testRectangleCollision(t, nw, se, Point{-10, 1}, Point{-3, -1}, false, "Outside west")
-	testRectangleCollision(t, nw, se, Point{-10, 3}, Point{-3, -3}, false, "Outside west")
-	testRectangleCollision(t, nw, se, Point{3, 1}, Point{10, -1}, false, "Outside east")
-	testRectangleCollision(t, nw, se, Point{3, 3}, Point{10, -3}, false, "Outside east")
-	testRectangleCollision(t, nw, se, Point{-1, 10}, Point{1, 3}, false, "Outside north")
-	testRectangleCollision(t, nw, se, Point{-3, 10}, Point{3, 3}, false, "Outside north")
-	testRectangleCollision(t, nw, se, Point{-1, -3}, Point{1, -10}, false, "Outside south")
-	testRectangleCollision(t, nw, se, Point{-3, -3}, Point{3, -10}, false, "Outside south")
+func TestBoundingBoxIntersects(t *testing.T) {
+	box := NewBoundingBox(-2, 2, -2, 2)
+
+	testBoundingBoxIntersects(t, box, box, true, "Same rectangle")
+	testBoundingBoxIntersects(t, box, NewBoundingBox(-1, 1, -1, 1), true, "Inside contains")
+	testBoundingBoxIntersects(t, box, NewBoundingBox(-3, 3, -3, 3), true, "Outside contains")
+	testBoundingBoxIntersects(t, box, NewBoundingBox(0, 10, -10, 10), true, "Left edge")
+	testBoundingBoxIntersects(t, box, NewBoundingBox(-10, 0, -10, 10), true, "Right edge")
+	testBoundingBoxIntersects(t, box, NewBoundingBox(-10, 10, -10, 0), true, "Top edge")
+	testBoundingBoxIntersects(t, box, NewBoundingBox(-10, 10, 0, 10), true, "Bottom edge")
+
+	testBoundingBoxIntersects(t, box, NewBoundingBox(-10, 0, -10, 0), true, "NW corner")
+	testBoundingBoxIntersects(t, box, NewBoundingBox(0, 10, -10, 0), true, "NE corner")
+	testBoundingBoxIntersects(t, box, NewBoundingBox(0, 10, 0, 10), true, "SE corner")
+	testBoundingBoxIntersects(t, box, NewBoundingBox(-10, 0, 0, 10), true, "SW corner")
+
+	testBoundingBoxIntersects(t, box, NewBoundingBox(-10, -4, -1, 1), false, "Outside west")
+	testBoundingBoxIntersects(t, box, NewBoundingBox(-10, -4, -4, 4), false, "Outside west")
+	testBoundingBoxIntersects(t, box, NewBoundingBox(4, 10, -1, 1), false, "Outside east")
+	testBoundingBoxIntersects(t, box, NewBoundingBox(4, 10, -4, 4), false, "Outside east")
+	testBoundingBoxIntersects(t, box, NewBoundingBox(-1, 1, 4, 10), false, "Outside north")
+	testBoundingBoxIntersects(t, box, NewBoundingBox(-4, 4, 4, 10), false, "Outside north")
+	testBoundingBoxIntersects(t, box, NewBoundingBox(-1, 1, -10, -4), false, "Outside south")
+	testBoundingBoxIntersects(t, box, NewBoundingBox(-4, 4, -10, -4), false, "Outside south")
 }
 
-// Create the smallest allowed node, 2x2, fill it, and verify contents
+// Create the smallest allowed node, fill it, and verify contents. Unlike the
+// pre-generics version, the 2x2 corner case here needs no special handling:
+// exact center+half-dimension subdivision places every point correctly.
 func TestFullNode(t *testing.T) {
-	points := [...]Point{
-		Point{0, 1},
-		Point{1, 1},
-		Point{1, 0},
-		Point{0, 0},
-	}
-
-	// There was a rounding bug when choosing quadrants for a 2x2 square when the
-	// points were negative, thus we run two tests.  Once with positive bounds,
-	// again with negative.
-	for delta := 0; delta >= -1; delta-- {
-		node := new(Node)
-		node.nw = modifyPoint(Point{0, 1}, delta, delta)
-		node.se = modifyPoint(Point{1, 0}, delta, delta)
-
-		for i, point := range points {
-			point := modifyPoint(point, delta, delta)
-			node.Insert(point, i)
+	tree := NewTree[float64, int](-1, 1, -1, 1, 0)
+	points := [...]Point[float64]{
+		{-0.5, 0.5},
+		{0.5, 0.5},
+		{0.5, -0.5},
+		{-0.5, -0.5},
+	}
+
+	for i, point := range points {
+		if err := tree.Insert(point, i); err != nil {
+			t.Fatal(err)
 		}
+	}
 
-		for i, point := range points {
-			point := modifyPoint(point, delta, delta)
-			leaf := node.nodes[i].(*Leaf)
-			if leaf.point != point {
-				t.Errorf("Expected point %s at index %d, but got %s", point, i, leaf.point)
-			}
-			if len(leaf.elems) != 1 || leaf.elems[0] != i {
-				t.Errorf("Expected val %d, but got %s", i, leaf.elems)
-			}
-			if actual := node.Find(point); !reflect.DeepEqual(leaf.elems, actual) {
-				t.Errorf("Expected %s, but got %s", leaf.elems, actual)
-			}
+	for i, point := range points {
+		leaf, ok := tree.nodes[i].(*Leaf[float64, int])
+		if !ok {
+			t.Fatalf("Expected a leaf at index %d, got %T", i, tree.nodes[i])
+		}
+		if len(leaf.entries) != 1 || leaf.entries[0].point != point {
+			t.Errorf("Expected point %v at index %d, but got %v", point, i, leaf.entries)
+		}
+		if actual := tree.Find(point); !reflect.DeepEqual(leaf.entries[0].elems, actual) {
+			t.Errorf("Expected %v, but got %v", leaf.entries[0].elems, actual)
 		}
 	}
 }
 
 // Test basic insert and find operations
 func TestBasicOperation(t *testing.T) {
-	tree := NewTree(-10, 10, -10, 10)
-	point1 := Point{3, 1}
-	point2 := Point{-2, 8}
-	point3 := Point{4, 2}
+	tree := NewTree[int, int](-10, 10, -10, 10, 0)
+	point1 := Point[int]{3, 1}
+	point2 := Point[int]{-2, 8}
+	point3 := Point[int]{4, 2}
 
 	if tree.Find(point1) != nil {
 		t.Error("Expected not to find point before inserting")
 	}
 
-	if err := tree.Insert(Point{11, 0}, 1); err == nil {
+	if err := tree.Insert(Point[int]{11, 0}, 1); err == nil {
 		t.Error("Expected error for inserting point out of bounds")
 	}
 
@@ -166,7 +148,7 @@ func TestBasicOperation(t *testing.T) {
 
 // Create a tree 21 x 21 and fill it in random order, verify all values are present
 func TestFullTree(t *testing.T) {
-	tree := NewTree(-10, 10, -10, 10)
+	tree := NewTree[int, int](-10, 10, -10, 10, 0)
 
 	// insert points in random order
 	rand.Seed(time.Now().Unix())
@@ -174,35 +156,35 @@ func TestFullTree(t *testing.T) {
 	for i := range order {
 		x := i%21 - 10
 		y := i/21 - 10
-		validateInsert(t, tree, Point{x, y}, i)
+		validateInsert(t, tree, Point[int]{x, y}, i)
 	}
 
 	for y := -10; y <= 10; y++ {
 		for x := -10; x <= 10; x++ {
 			expected := (y+10)*21 + (x + 10)
-			validateFind(t, tree, Point{x, y}, expected)
+			validateFind(t, tree, Point[int]{x, y}, expected)
 		}
 	}
 
-	validateFindRange(t, tree, Point{-10, -10}, Point{-10, -10}, 0)
-	validateFindRange(t, tree, Point{-7, -7}, Point{-6, -7}, 66, 67)
-	validateFindRange(t, tree, Point{10, -7}, Point{-10, -7})
-	validateFindRange(t, tree, Point{-1, 1}, Point{1, -1},
+	validateFindRange(t, tree, -10, -10, -10, -10, 0)
+	validateFindRange(t, tree, -7, -5, -7, -7, 66, 67, 68)
+	validateFindRange(t, tree, 10, -10, -7, -7)
+	validateFindRange(t, tree, -1, 1, -1, 1,
 		198, 199, 200, 219, 220, 221, 240, 241, 242)
 }
 
-// Delete is probably broken, well I definitely am not cleaning up the tree
-// properly and I'm not checking any corner cases
+// Delete removes values from a leaf and, once a leaf's last value is gone,
+// prunes its now-empty slot from the tree.
 func TestBasicDelete(t *testing.T) {
-	tree := NewTree(-10, 10, -10, 10)
+	tree := NewTree[int, int](-10, 10, -10, 10, 0)
 
-	point := Point{4, 5}
+	point := Point[int]{4, 5}
 
 	validateInsert(t, tree, point, 4)
 	validateInsert(t, tree, point, 5)
 	validateFind(t, tree, point, 4, 5)
 
-	if tree.Delete(Point{0, 20}, 4) {
+	if tree.Delete(Point[int]{0, 20}, 4) {
 		t.Error("Delete succeeded on an out of bounds point")
 	}
 
@@ -218,4 +200,73 @@ func TestBasicDelete(t *testing.T) {
 	if !tree.Delete(point, 5) {
 		t.Error("Delete failed for a valid point/value")
 	}
-}
\ No newline at end of file
+
+	_, quadrant := chooseQuadrant(tree.bounds, point)
+	if tree.nodes[quadrant] != nil {
+		t.Errorf("Expected slot %d to be pruned after deleting its only leaf, got %T",
+			quadrant, tree.nodes[quadrant])
+	}
+}
+
+// countNonNil counts the tree's non-nil slots, recursing into child nodes.
+func countNonNil(node *Node[int, int]) int {
+	var count int
+	for _, slot := range node.nodes {
+		switch next := slot.(type) {
+		case *Leaf[int, int]:
+			count++
+		case *Node[int, int]:
+			count += countNonNil(next)
+		}
+	}
+	return count
+}
+
+// Insert a grid of points, delete them all back out in a few different
+// orders, and verify the tree fully collapses to an empty root each time.
+func TestDeleteCompaction(t *testing.T) {
+	var points []Point[int]
+	for x := -5; x <= 5; x++ {
+		for y := -5; y <= 5; y++ {
+			points = append(points, Point[int]{x, y})
+		}
+	}
+
+	orders := [][]int{
+		nil, // insertion order
+	}
+	forward := make([]int, len(points))
+	reverse := make([]int, len(points))
+	for i := range points {
+		forward[i] = i
+		reverse[i] = len(points) - 1 - i
+	}
+	orders[0] = forward
+	orders = append(orders, reverse)
+
+	shuffled := append([]int(nil), forward...)
+	rand.Seed(42)
+	rand.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+	orders = append(orders, shuffled)
+
+	for _, order := range orders {
+		tree := NewTree[int, int](-10, 10, -10, 10, 0)
+		for _, p := range points {
+			validateInsert(t, tree, p, p.X*100+p.Y)
+		}
+
+		for _, i := range order {
+			p := points[i]
+			if !tree.Delete(p, p.X*100+p.Y) {
+				t.Fatalf("Delete failed for point %v", p)
+			}
+		}
+
+		if count := countNonNil(tree); count != 0 {
+			t.Errorf("Expected tree to fully collapse, but %d slots remain", count)
+		}
+		if tree.TotalMass() != 0 {
+			t.Errorf("Expected TotalMass() == 0 after deleting everything, got %v", tree.TotalMass())
+		}
+	}
+}